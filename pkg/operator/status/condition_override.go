@@ -0,0 +1,144 @@
+package status
+
+import (
+	"fmt"
+	"regexp"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+// Severity classifies how seriously a ConditionOverride treats its source condition.
+type Severity string
+
+const (
+	// SeverityError behaves like an unoverridden condition: it can drive its aggregated
+	// condition to its non-default status.
+	SeverityError Severity = "Error"
+	// SeverityWarning can still surface in the aggregated message (prefixed with
+	// "[warning]"), but never drives a "*Degraded" condition to True -- absent an explicit
+	// OverrideOutputType, a Warning-severity "*Degraded" source condition is redirected to
+	// the matching "*Progressing" condition instead.
+	SeverityWarning Severity = "Warning"
+	// SeverityInfo never affects any aggregated condition's status; it can still be
+	// redirected via OverrideOutputType purely to surface its message elsewhere.
+	SeverityInfo Severity = "Info"
+)
+
+// ConditionOverride changes how the operator conditions matching a registered regex are
+// interpreted before StatusSyncer's normal inertia and merge-strategy processing runs.
+type ConditionOverride struct {
+	// Polarity, when true, inverts the condition's Status (True<->False; Unknown is left
+	// alone) before it's evaluated against its aggregated type's default status. Useful for
+	// operators that emit a condition with inverted truthiness, e.g. a "*Healthy" condition
+	// where True means good.
+	Polarity bool
+	// Severity downgrades how the condition can affect the aggregated status; see the
+	// Severity constants.
+	Severity Severity
+	// OverrideOutputType, if set, redirects the condition to feed a different aggregated
+	// ClusterOperator condition type than its own Type would normally select, e.g. a
+	// "FooWarning" operator condition feeding configv1.OperatorUpgradeable instead of
+	// configv1.OperatorDegraded. Redirection preserves whatever prefix the registering
+	// regex's first capturing group matched, so a regex used for redirection should capture
+	// it, e.g. regexp.MustCompile(`^(.*)Warning$`).
+	OverrideOutputType configv1.ClusterStatusConditionType
+}
+
+type conditionOverrideEntry struct {
+	typeRegex *regexp.Regexp
+	override  ConditionOverride
+}
+
+// WithConditionOverride registers an override applied to every operator condition whose Type
+// matches typeRegex, before inertia and merge strategies run. The first matching override
+// wins; overrides are tried in registration order.
+func (c *StatusSyncer) WithConditionOverride(typeRegex *regexp.Regexp, override ConditionOverride) *StatusSyncer {
+	c.conditionOverrides = append(c.conditionOverrides, conditionOverrideEntry{typeRegex: typeRegex, override: override})
+	return c
+}
+
+// aggregatedDefaultStatus is the "good" status for each condition type StatusSyncer knows how
+// to aggregate, used to make a SeverityInfo condition inert for status purposes.
+var aggregatedDefaultStatus = map[configv1.ClusterStatusConditionType]configv1.ConditionStatus{
+	configv1.OperatorDegraded:    configv1.ConditionFalse,
+	configv1.OperatorProgressing: configv1.ConditionFalse,
+	configv1.OperatorAvailable:   configv1.ConditionTrue,
+	configv1.OperatorUpgradeable: configv1.ConditionTrue,
+}
+
+// applyConditionOverrides returns conditions with every registered ConditionOverride applied:
+// polarity inverted, severity-driven redirection/downgrading done, and Warning messages
+// prefixed. Conditions matching no override pass through unchanged.
+func (c *StatusSyncer) applyConditionOverrides(conditions []operatorv1.OperatorCondition) []operatorv1.OperatorCondition {
+	if len(c.conditionOverrides) == 0 {
+		return conditions
+	}
+
+	out := make([]operatorv1.OperatorCondition, len(conditions))
+	for i, condition := range conditions {
+		override, prefix, ok := c.findConditionOverride(condition.Type)
+		if !ok {
+			out[i] = condition
+			continue
+		}
+
+		if override.Polarity {
+			switch condition.Status {
+			case operatorv1.ConditionTrue:
+				condition.Status = operatorv1.ConditionFalse
+			case operatorv1.ConditionFalse:
+				condition.Status = operatorv1.ConditionTrue
+			}
+		}
+
+		switch {
+		case len(override.OverrideOutputType) > 0:
+			condition.Type = prefix + string(override.OverrideOutputType)
+		case override.Severity == SeverityWarning && hasSuffixType(condition.Type, configv1.OperatorDegraded):
+			condition.Type = prefix + string(configv1.OperatorProgressing)
+		}
+
+		if override.Severity == SeverityInfo {
+			if defaultStatus, ok := aggregatedDefaultStatusFor(condition.Type); ok {
+				condition.Status = defaultStatus
+			}
+		}
+
+		if override.Severity == SeverityWarning && len(condition.Message) > 0 {
+			condition.Message = fmt.Sprintf("[warning] %s", condition.Message)
+		}
+
+		out[i] = condition
+	}
+	return out
+}
+
+func (c *StatusSyncer) findConditionOverride(conditionType string) (ConditionOverride, string, bool) {
+	for _, entry := range c.conditionOverrides {
+		match := entry.typeRegex.FindStringSubmatch(conditionType)
+		if match == nil {
+			continue
+		}
+		prefix := conditionType
+		if len(match) > 1 {
+			prefix = match[1]
+		}
+		return entry.override, prefix, true
+	}
+	return ConditionOverride{}, "", false
+}
+
+func hasSuffixType(conditionType string, suffix configv1.ClusterStatusConditionType) bool {
+	s := string(suffix)
+	return len(conditionType) >= len(s) && conditionType[len(conditionType)-len(s):] == s
+}
+
+func aggregatedDefaultStatusFor(conditionType string) (configv1.ConditionStatus, bool) {
+	for suffix, status := range aggregatedDefaultStatus {
+		if hasSuffixType(conditionType, suffix) {
+			return status, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,68 @@
+package status
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+// InertiaFunc returns the minimum amount of time an operator condition must have spent in a
+// non-default state before StatusSyncer lets it affect the corresponding aggregated
+// ClusterOperator condition. It exists so that noisy, flapping operator conditions don't
+// immediately flip the ClusterOperator status that the rest of the cluster reacts to.
+type InertiaFunc func(operatorv1.OperatorCondition) time.Duration
+
+// noInertia is the InertiaFunc used for aggregated conditions that haven't opted into
+// debouncing: every non-default condition counts immediately.
+func noInertia(operatorv1.OperatorCondition) time.Duration {
+	return 0
+}
+
+// InertiaCondition associates a Duration with every operator condition whose Type matches
+// ConditionTypeMatcher.
+type InertiaCondition struct {
+	// ConditionTypeMatcher selects the operator conditions this entry applies to.
+	ConditionTypeMatcher *regexp.Regexp
+	// Duration is how long the condition must remain in its reported (non-default) state
+	// before it is allowed to affect the aggregated condition.
+	Duration time.Duration
+}
+
+// Inertia builds an InertiaFunc out of a default duration and a set of per-type overrides.
+type Inertia struct {
+	defaultDuration time.Duration
+	conditions      []InertiaCondition
+}
+
+// NewInertia constructs an Inertia, returning an error if any entry is malformed.
+func NewInertia(defaultDuration time.Duration, conditions ...InertiaCondition) (*Inertia, error) {
+	for i, condition := range conditions {
+		if condition.ConditionTypeMatcher == nil {
+			return nil, fmt.Errorf("inertia condition %d: ConditionTypeMatcher is required", i)
+		}
+	}
+	return &Inertia{defaultDuration: defaultDuration, conditions: conditions}, nil
+}
+
+// MustNewInertia is like NewInertia but panics on error. It is meant for inertia tables that
+// are built once from statically known condition types, not from user-controlled input.
+func MustNewInertia(defaultDuration time.Duration, conditions ...InertiaCondition) *Inertia {
+	inertia, err := NewInertia(defaultDuration, conditions...)
+	if err != nil {
+		panic(err)
+	}
+	return inertia
+}
+
+// Inertia is an InertiaFunc and can be passed directly to WithDegradedInertia,
+// WithProgressingInertia or WithAvailableInertia.
+func (i *Inertia) Inertia(condition operatorv1.OperatorCondition) time.Duration {
+	for _, entry := range i.conditions {
+		if entry.ConditionTypeMatcher.MatchString(condition.Type) {
+			return entry.Duration
+		}
+	}
+	return i.defaultDuration
+}
@@ -0,0 +1,190 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+// MergedCondition is a single operator condition feeding an aggregated ClusterOperator
+// condition, annotated with whether it currently counts toward that condition's
+// status/reason. A condition Counts when its status differs from the aggregated type's default
+// and at least one such condition has cleared the InertiaFunc governing that type; conditions
+// that don't count are still reported here so a MergeStrategy can choose to surface their
+// message anyway.
+type MergedCondition struct {
+	operatorv1.OperatorCondition
+	Counts bool
+}
+
+// MergeStrategy collapses the operator conditions that feed a single aggregated
+// ClusterOperator condition (e.g. all "*Degraded" conditions) into that condition's status,
+// reason and message. StatusSyncer calls Merge once per aggregated condition type, every sync,
+// unless no operator condition feeds that type (it then reports NoData without calling Merge).
+type MergeStrategy interface {
+	Merge(conditionType configv1.ClusterStatusConditionType, defaultStatus configv1.ConditionStatus, conditions []MergedCondition) (status configv1.ConditionStatus, reason string, message string)
+}
+
+// defaultMergeStrategy reproduces StatusSyncer's original behavior: every counting condition
+// contributes its trimmed type (plus "_Reason" when set) to a "::"-joined reason, the most
+// severe counting condition's status wins, and every counting condition with a non-empty
+// message contributes one "Type: line" entry per message line. When no condition counts, every
+// condition with a non-empty message contributes instead.
+type defaultMergeStrategy struct{}
+
+func (defaultMergeStrategy) Merge(conditionType configv1.ClusterStatusConditionType, defaultStatus configv1.ConditionStatus, conditions []MergedCondition) (configv1.ConditionStatus, string, string) {
+	status := defaultStatus
+	reason := "AsExpected"
+	message := joinMessages(conditionsOf(conditions))
+
+	var found []operatorv1.OperatorCondition
+	for _, condition := range conditions {
+		if condition.Counts {
+			found = append(found, condition.OperatorCondition)
+		}
+	}
+	if len(found) > 0 {
+		message = joinMessages(found)
+		sort.SliceStable(found, func(i, j int) bool {
+			return conditionSeverity(found[i].Status, defaultStatus) > conditionSeverity(found[j].Status, defaultStatus)
+		})
+		status = found[0].Status
+		reasons := make([]string, 0, len(found))
+		for _, condition := range found {
+			reasons = append(reasons, mergeReason(conditionType, condition))
+		}
+		reason = strings.Join(reasons, "::")
+	}
+
+	return status, reason, message
+}
+
+// PriorityMergeStrategy picks the single highest-severity counting condition and reports only
+// that one, instead of joining every counting condition's type into the reason. Useful when a
+// controller has many redundant conditions feeding the same aggregated type and doesn't want
+// reason strings like "TypeA::TypeB::TypeC".
+type PriorityMergeStrategy struct {
+	// Severity maps an operator condition's Type to its severity; higher wins. Types absent
+	// from the map are treated as severity 0.
+	Severity map[string]int
+}
+
+func (p PriorityMergeStrategy) Merge(conditionType configv1.ClusterStatusConditionType, defaultStatus configv1.ConditionStatus, conditions []MergedCondition) (configv1.ConditionStatus, string, string) {
+	var found []operatorv1.OperatorCondition
+	for _, condition := range conditions {
+		if condition.Counts {
+			found = append(found, condition.OperatorCondition)
+		}
+	}
+	if len(found) == 0 {
+		return defaultStatus, "AsExpected", joinMessages(conditionsOf(conditions))
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return p.Severity[found[i].Type] > p.Severity[found[j].Type]
+	})
+	winner := found[0]
+
+	var message string
+	if len(winner.Message) > 0 {
+		message = fmt.Sprintf("%s: %s", winner.Type, winner.Message)
+	}
+	return winner.Status, mergeReason(conditionType, winner), message
+}
+
+// CountingMergeStrategy summarizes how many of the feeding conditions count as failing
+// instead of naming every one of them, e.g. "3 of 5 subcontrollers degraded" plus a bulleted
+// breakdown of which ones.
+type CountingMergeStrategy struct {
+	// Noun names what is being counted, e.g. "subcontrollers". Defaults to "conditions".
+	Noun string
+	// OverrideType keeps specific operator condition Types out of the failing count, e.g. so a
+	// Warning-severity condition doesn't flip the aggregated status. Only entries whose value
+	// equals the aggregated type's defaultStatus have an effect; entries with any other value
+	// are ignored.
+	OverrideType map[string]configv1.ConditionStatus
+}
+
+func (c CountingMergeStrategy) Merge(conditionType configv1.ClusterStatusConditionType, defaultStatus configv1.ConditionStatus, conditions []MergedCondition) (configv1.ConditionStatus, string, string) {
+	noun := c.Noun
+	if len(noun) == 0 {
+		noun = "conditions"
+	}
+
+	var failing []operatorv1.OperatorCondition
+	for _, condition := range conditions {
+		if !condition.Counts {
+			continue
+		}
+		if override, ok := c.OverrideType[condition.Type]; ok && override == defaultStatus {
+			continue
+		}
+		failing = append(failing, condition.OperatorCondition)
+	}
+	if len(failing) == 0 {
+		return defaultStatus, "AsExpected", joinMessages(conditionsOf(conditions))
+	}
+
+	sort.SliceStable(failing, func(i, j int) bool {
+		return conditionSeverity(failing[i].Status, defaultStatus) > conditionSeverity(failing[j].Status, defaultStatus)
+	})
+
+	lines := make([]string, 0, len(failing)+1)
+	lines = append(lines, fmt.Sprintf("%d of %d %s %s", len(failing), len(conditions), noun, strings.ToLower(string(conditionType))))
+	reasons := make([]string, 0, len(failing))
+	for _, condition := range failing {
+		reasons = append(reasons, mergeReason(conditionType, condition))
+		lines = append(lines, fmt.Sprintf("- %s: %s", condition.Type, condition.Message))
+	}
+
+	return failing[0].Status, strings.Join(reasons, "::"), strings.Join(lines, "\n")
+}
+
+// conditionSeverity ranks a condition's status relative to defaultStatus: the status that
+// isn't the default and isn't Unknown is the most severe, Unknown is next, and the default
+// itself is least severe.
+func conditionSeverity(status, defaultStatus configv1.ConditionStatus) int {
+	switch {
+	case status == defaultStatus:
+		return 0
+	case status == configv1.ConditionUnknown:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// mergeReason renders one operator condition's contribution to a "::"-joined reason.
+func mergeReason(conditionType configv1.ClusterStatusConditionType, condition operatorv1.OperatorCondition) string {
+	name := strings.TrimSuffix(condition.Type, string(conditionType))
+	if len(condition.Reason) > 0 {
+		return name + "_" + condition.Reason
+	}
+	return name
+}
+
+// joinMessages renders every given condition with a non-empty message as one "Type: line" entry
+// per message line, in the order given.
+func joinMessages(conditions []operatorv1.OperatorCondition) string {
+	var lines []string
+	for _, condition := range conditions {
+		if len(condition.Message) == 0 {
+			continue
+		}
+		for _, line := range strings.Split(condition.Message, "\n") {
+			lines = append(lines, fmt.Sprintf("%s: %s", condition.Type, line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func conditionsOf(merged []MergedCondition) []operatorv1.OperatorCondition {
+	conditions := make([]operatorv1.OperatorCondition, 0, len(merged))
+	for _, condition := range merged {
+		conditions = append(conditions, condition.OperatorCondition)
+	}
+	return conditions
+}
@@ -0,0 +1,154 @@
+package status
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/client-go/config/clientset/versioned/fake"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+
+	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestConditionMergeStrategies(t *testing.T) {
+	threeMinutesAgo := metav1.NewTime(time.Now().Add(-3 * time.Minute))
+
+	testCases := []struct {
+		name             string
+		strategy         MergeStrategy
+		conditions       []operatorv1.OperatorCondition
+		expectedStatus   configv1.ConditionStatus
+		expectedReason   string
+		expectedMessages []string
+	}{
+		{
+			name: "priority strategy reports only the winner",
+			strategy: PriorityMergeStrategy{
+				Severity: map[string]int{
+					"TypeADegraded": 1,
+					"TypeBDegraded": 10,
+				},
+			},
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeADegraded", Status: operatorv1.ConditionTrue, LastTransitionTime: threeMinutesAgo, Message: "a message from type a"},
+				{Type: "TypeBDegraded", Status: operatorv1.ConditionTrue, LastTransitionTime: threeMinutesAgo, Message: "a message from type b", Reason: "Oops"},
+			},
+			expectedStatus: configv1.ConditionTrue,
+			expectedReason: "TypeB_Oops",
+			expectedMessages: []string{
+				"TypeBDegraded: a message from type b",
+			},
+		},
+		{
+			name:     "priority strategy falls back to AsExpected when nothing counts",
+			strategy: PriorityMergeStrategy{},
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeADegraded", Status: operatorv1.ConditionFalse, LastTransitionTime: threeMinutesAgo, Message: "a message from type a"},
+			},
+			expectedStatus: configv1.ConditionFalse,
+			expectedReason: "AsExpected",
+			expectedMessages: []string{
+				"TypeADegraded: a message from type a",
+			},
+		},
+		{
+			name:     "counting strategy summarizes how many are failing",
+			strategy: CountingMergeStrategy{Noun: "subcontrollers"},
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeADegraded", Status: operatorv1.ConditionTrue, LastTransitionTime: threeMinutesAgo, Message: "a is broken"},
+				{Type: "TypeBDegraded", Status: operatorv1.ConditionFalse, LastTransitionTime: threeMinutesAgo},
+				{Type: "TypeCDegraded", Status: operatorv1.ConditionTrue, LastTransitionTime: threeMinutesAgo, Message: "c is broken"},
+			},
+			expectedStatus: configv1.ConditionTrue,
+			expectedReason: "TypeA::TypeC",
+			expectedMessages: []string{
+				"2 of 3 subcontrollers degraded",
+				"- TypeADegraded: a is broken",
+				"- TypeCDegraded: c is broken",
+			},
+		},
+		{
+			name: "counting strategy override downgrades a warning back to passing",
+			strategy: CountingMergeStrategy{
+				Noun:         "subcontrollers",
+				OverrideType: map[string]configv1.ConditionStatus{"TypeCDegraded": configv1.ConditionFalse},
+			},
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeADegraded", Status: operatorv1.ConditionTrue, LastTransitionTime: threeMinutesAgo, Message: "a is broken"},
+				{Type: "TypeCDegraded", Status: operatorv1.ConditionTrue, LastTransitionTime: threeMinutesAgo, Message: "c is just a warning"},
+			},
+			expectedStatus: configv1.ConditionTrue,
+			expectedReason: "TypeA",
+			expectedMessages: []string{
+				"1 of 2 subcontrollers degraded",
+				"- TypeADegraded: a is broken",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterOperator := &configv1.ClusterOperator{
+				ObjectMeta: metav1.ObjectMeta{Name: "OPERATOR_NAME", ResourceVersion: "12"},
+			}
+			clusterOperatorClient := fake.NewSimpleClientset(clusterOperator)
+
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			indexer.Add(clusterOperator)
+
+			statusClient := &statusClient{
+				t:      t,
+				status: operatorv1.OperatorStatus{Conditions: tc.conditions},
+			}
+			controller := &StatusSyncer{
+				clusterOperatorName:   "OPERATOR_NAME",
+				clusterOperatorClient: clusterOperatorClient.ConfigV1(),
+				clusterOperatorLister: configv1listers.NewClusterOperatorLister(indexer),
+				operatorClient:        statusClient,
+				versionGetter:         NewVersionGetter(),
+			}
+			controller = controller.WithConditionMergeStrategy(configv1.OperatorDegraded, tc.strategy)
+
+			if err := controller.Sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("status"))); err != nil {
+				t.Fatalf("unexpected sync error: %v", err)
+			}
+
+			result, _ := clusterOperatorClient.ConfigV1().ClusterOperators().Get(context.TODO(), "OPERATOR_NAME", metav1.GetOptions{})
+			for i := range result.Status.Conditions {
+				result.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+			}
+
+			expected := &configv1.ClusterOperatorStatusCondition{
+				Type:   configv1.OperatorDegraded,
+				Status: tc.expectedStatus,
+				Reason: tc.expectedReason,
+			}
+			if len(tc.expectedMessages) > 0 {
+				expected.Message = joinLines(tc.expectedMessages)
+			}
+
+			actual := v1helpers.FindStatusCondition(result.Status.Conditions, configv1.OperatorDegraded)
+			if !reflect.DeepEqual(expected, actual) {
+				t.Error(diff.ObjectDiff(expected, actual))
+			}
+		})
+	}
+}
+
+func joinLines(lines []string) string {
+	out := lines[0]
+	for _, line := range lines[1:] {
+		out += "\n" + line
+	}
+	return out
+}
@@ -0,0 +1,190 @@
+package status
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+)
+
+// NewStatusUpdateRateLimiter returns a fast/slow workqueue.RateLimiter for use with
+// WithUpdateRateLimiter (StatusSyncer uses no limiter unless one is set): the first 20 status updates
+// go through immediately, the next 200 are spaced 200ms apart (~5/s), and everything after
+// that backs off to a flat 30s. It exists so that a flapping operator condition produces a
+// bounded number of ClusterOperator PATCHes instead of one per reconcile.
+func NewStatusUpdateRateLimiter() workqueue.RateLimiter {
+	return newPhaseRateLimiter(20, 200, 200*time.Millisecond, 30*time.Second)
+}
+
+// phaseRateLimiter implements workqueue.RateLimiter with three phases keyed by how many times
+// an item has been seen: an immediate burst, a fast steady-state, then a flat slow phase. A
+// count decays back to zero once an item has gone quiet for at least maxDelay, so a long-running
+// item isn't permanently stuck in the slow phase by calls from weeks ago -- only sustained,
+// ongoing churn advances it through the phases.
+type phaseRateLimiter struct {
+	immediateBurst int
+	fastCount      int
+	fastInterval   time.Duration
+	maxDelay       time.Duration
+
+	now func() time.Time
+
+	lock     sync.Mutex
+	counts   map[interface{}]int
+	lastSeen map[interface{}]time.Time
+}
+
+func newPhaseRateLimiter(immediateBurst, fastCount int, fastInterval, maxDelay time.Duration) *phaseRateLimiter {
+	return &phaseRateLimiter{
+		immediateBurst: immediateBurst,
+		fastCount:      fastCount,
+		fastInterval:   fastInterval,
+		maxDelay:       maxDelay,
+		now:            time.Now,
+		counts:         map[interface{}]int{},
+		lastSeen:       map[interface{}]time.Time{},
+	}
+}
+
+func (r *phaseRateLimiter) When(item interface{}) time.Duration {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	if last, ok := r.lastSeen[item]; ok && now.Sub(last) >= r.maxDelay {
+		delete(r.counts, item)
+	}
+	r.lastSeen[item] = now
+
+	r.counts[item]++
+	switch n := r.counts[item]; {
+	case n <= r.immediateBurst:
+		return 0
+	case n <= r.immediateBurst+r.fastCount:
+		return r.fastInterval
+	default:
+		return r.maxDelay
+	}
+}
+
+func (r *phaseRateLimiter) Forget(item interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.counts, item)
+	delete(r.lastSeen, item)
+}
+
+func (r *phaseRateLimiter) NumRequeues(item interface{}) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.counts[item]
+}
+
+// cancelableTimer is the subset of *time.Timer coalescingStatusWriter relies on. Tests
+// substitute a fake clock implementation so cooldowns can be advanced without a real sleep.
+type cancelableTimer interface {
+	Stop() bool
+}
+
+// coalescingStatusWriter collapses repeated ClusterOperator status writes behind a
+// workqueue.RateLimiter: while a write is cooling down, further writes just replace the
+// pending desired status, and only the latest one is flushed once the limiter allows it.
+type coalescingStatusWriter struct {
+	client    configv1client.ClusterOperatorsGetter
+	limiter   workqueue.RateLimiter
+	afterFunc func(d time.Duration, f func()) cancelableTimer
+
+	lock    sync.Mutex
+	pending *configv1.ClusterOperator
+	timer   cancelableTimer
+}
+
+func newCoalescingStatusWriter(client configv1client.ClusterOperatorsGetter, limiter workqueue.RateLimiter) *coalescingStatusWriter {
+	return &coalescingStatusWriter{
+		client:  client,
+		limiter: limiter,
+		afterFunc: func(d time.Duration, f func()) cancelableTimer {
+			return time.AfterFunc(d, f)
+		},
+	}
+}
+
+// Write schedules desired to be written to the ClusterOperator API, coalescing it with any
+// write already cooling down. force skips the rate limiter's cooldown, writes immediately and
+// resets the limiter's count for the object (Forget); callers must force status transitions that flip Available/Degraded, and the
+// final write on shutdown (see Flush).
+func (w *coalescingStatusWriter) Write(ctx context.Context, desired *configv1.ClusterOperator, force bool) error {
+	w.lock.Lock()
+	w.pending = desired
+
+	if force {
+		if w.timer != nil {
+			w.timer.Stop()
+			w.timer = nil
+		}
+		pending := w.pending
+		w.pending = nil
+		w.lock.Unlock()
+		w.limiter.Forget(desired.Name)
+		return w.write(ctx, pending)
+	}
+
+	if w.timer != nil {
+		// a flush is already scheduled; it will pick up the pending status set above.
+		w.lock.Unlock()
+		return nil
+	}
+
+	delay := w.limiter.When(desired.Name)
+	if delay <= 0 {
+		pending := w.pending
+		w.pending = nil
+		w.lock.Unlock()
+		return w.write(ctx, pending)
+	}
+
+	w.timer = w.afterFunc(delay, func() {
+		w.lock.Lock()
+		pending := w.pending
+		w.pending = nil
+		w.timer = nil
+		w.lock.Unlock()
+		if pending == nil {
+			return
+		}
+		if err := w.write(context.Background(), pending); err != nil {
+			klog.Errorf("Error writing status of %s: %v", pending.Name, err)
+		}
+	})
+	w.lock.Unlock()
+	return nil
+}
+
+// Flush forces any pending status write through immediately, bypassing the rate limiter's
+// cooldown. It is meant to be called on controller shutdown so the last known status isn't
+// lost behind a cooldown that never gets to fire.
+func (w *coalescingStatusWriter) Flush(ctx context.Context) error {
+	w.lock.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	pending := w.pending
+	w.pending = nil
+	w.lock.Unlock()
+	return w.write(ctx, pending)
+}
+
+func (w *coalescingStatusWriter) write(ctx context.Context, desired *configv1.ClusterOperator) error {
+	if desired == nil {
+		return nil
+	}
+	_, err := w.client.ClusterOperators().UpdateStatus(ctx, desired, metav1.UpdateOptions{})
+	return err
+}
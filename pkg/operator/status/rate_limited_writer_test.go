@@ -0,0 +1,249 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/client-go/config/clientset/versioned/fake"
+)
+
+// fakeTimer is a cancelableTimer a fakeScheduler hands out; firing it runs the callback
+// synchronously instead of waiting on a real clock.
+type fakeTimer struct {
+	fire func()
+}
+
+func (t *fakeTimer) Stop() bool {
+	fired := t.fire == nil
+	t.fire = nil
+	return !fired
+}
+
+// fakeScheduler stands in for time.AfterFunc so tests can advance coalescingStatusWriter's
+// cooldown deterministically, without sleeping on the wall clock.
+type fakeScheduler struct {
+	pending *fakeTimer
+}
+
+func (s *fakeScheduler) afterFunc(_ time.Duration, f func()) cancelableTimer {
+	s.pending = &fakeTimer{fire: f}
+	return s.pending
+}
+
+// advance runs the currently scheduled callback, as if its delay had elapsed.
+func (s *fakeScheduler) advance() {
+	timer := s.pending
+	s.pending = nil
+	if timer != nil && timer.fire != nil {
+		fire := timer.fire
+		timer.fire = nil
+		fire()
+	}
+}
+
+func TestPhaseRateLimiter(t *testing.T) {
+	limiter := newPhaseRateLimiter(3, 2, 10*time.Millisecond, time.Hour)
+
+	expected := []time.Duration{0, 0, 0, 10 * time.Millisecond, 10 * time.Millisecond, time.Hour, time.Hour}
+	for i, want := range expected {
+		if got := limiter.When("item"); got != want {
+			t.Errorf("call %d: got %v, want %v", i, got, want)
+		}
+	}
+
+	limiter.Forget("item")
+	if got := limiter.When("item"); got != 0 {
+		t.Errorf("after Forget: got %v, want 0", got)
+	}
+}
+
+// TestPhaseRateLimiterDecaysAfterQuiesce exercises the scenario the three-phase design is meant
+// to bound: a long-running item that accumulates more than immediateBurst+fastCount calls over
+// its lifetime must not be stuck at maxDelay forever -- once it's been quiet for at least
+// maxDelay, the next call should start back at the immediate-burst phase. It uses an injected
+// clock rather than a real sleep so the scenario (hours of simulated quiet time) is instant and
+// deterministic.
+func TestPhaseRateLimiterDecaysAfterQuiesce(t *testing.T) {
+	limiter := newPhaseRateLimiter(1, 1, time.Minute, time.Hour)
+	clock := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return clock }
+
+	// Burn through immediateBurst+fastCount so the item is pinned at maxDelay, as a
+	// long-running item eventually would.
+	for i := 0; i < 3; i++ {
+		limiter.When("item")
+	}
+	if got := limiter.When("item"); got != time.Hour {
+		t.Fatalf("expected the item to have reached the slow phase, got delay %v", got)
+	}
+
+	// Going quiet for less than maxDelay must not reset it early.
+	clock = clock.Add(30 * time.Minute)
+	if got := limiter.When("item"); got != time.Hour {
+		t.Errorf("expected the item to still be in the slow phase before maxDelay has elapsed, got delay %v", got)
+	}
+
+	// Once quiet for at least maxDelay, the item should decay back to the immediate-burst phase.
+	clock = clock.Add(time.Hour)
+	if got := limiter.When("item"); got != 0 {
+		t.Errorf("expected the item to decay back to the immediate-burst phase after quiescing, got delay %v", got)
+	}
+}
+
+// constantRateLimiter always returns the same delay, regardless of how many times an item has
+// been seen -- handy for pinning down coalescingStatusWriter's behavior without depending on
+// phaseRateLimiter's own counting.
+type constantRateLimiter struct {
+	delay time.Duration
+}
+
+func (c constantRateLimiter) When(interface{}) time.Duration { return c.delay }
+func (c constantRateLimiter) Forget(interface{})             {}
+func (c constantRateLimiter) NumRequeues(interface{}) int    { return 0 }
+
+func TestCoalescingStatusWriter(t *testing.T) {
+	t.Run("immediate writes pass straight through", func(t *testing.T) {
+		clusterOperator := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "OPERATOR_NAME"}}
+		client := fake.NewSimpleClientset(clusterOperator)
+		writer := newCoalescingStatusWriter(client.ConfigV1(), constantRateLimiter{delay: 0})
+
+		for i := 0; i < 3; i++ {
+			if err := writer.Write(context.TODO(), clusterOperator.DeepCopy(), false); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if actions := len(client.Actions()); actions != 3 {
+			t.Errorf("expected 3 API calls, got %d", actions)
+		}
+	})
+
+	t.Run("writes during a cooldown collapse into one", func(t *testing.T) {
+		clusterOperator := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "OPERATOR_NAME"}}
+		client := fake.NewSimpleClientset(clusterOperator)
+		writer := newCoalescingStatusWriter(client.ConfigV1(), constantRateLimiter{delay: 50 * time.Millisecond})
+		scheduler := &fakeScheduler{}
+		writer.afterFunc = scheduler.afterFunc
+
+		for i := 0; i < 5; i++ {
+			desired := clusterOperator.DeepCopy()
+			desired.Status.Versions = []configv1.OperandVersion{{Name: "operator", Version: string(rune('0' + i))}}
+			if err := writer.Write(context.TODO(), desired, false); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		scheduler.advance()
+
+		if actions := len(client.Actions()); actions != 1 {
+			t.Errorf("expected the 5 writes to collapse into 1 API call, got %d", actions)
+		}
+		result, err := client.ConfigV1().ClusterOperators().Get(context.TODO(), "OPERATOR_NAME", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := result.Status.Versions[0].Version; got != "4" {
+			t.Errorf("expected the latest pending status to win, got version %q", got)
+		}
+	})
+
+	t.Run("sustained successful writes still advance through the documented phases", func(t *testing.T) {
+		// Regression coverage for resetting the limiter on every successful write: that would
+		// let a continuously-changing-but-healthy condition stay in the immediate-burst phase
+		// forever, contrary to NewStatusUpdateRateLimiter's documented bounded-rate behavior.
+		clusterOperator := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "OPERATOR_NAME"}}
+		client := fake.NewSimpleClientset(clusterOperator)
+		limiter := newPhaseRateLimiter(1, 1, time.Hour, time.Hour)
+		writer := newCoalescingStatusWriter(client.ConfigV1(), limiter)
+		scheduler := &fakeScheduler{}
+		writer.afterFunc = scheduler.afterFunc
+
+		for i := 0; i < 2; i++ {
+			desired := clusterOperator.DeepCopy()
+			desired.Status.Versions = []configv1.OperandVersion{{Name: "operator", Version: fmt.Sprintf("v%d", i)}}
+			if err := writer.Write(context.TODO(), desired, false); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			scheduler.advance()
+		}
+
+		// Budget (immediateBurst+fastCount) is spent; the next write must be throttled instead
+		// of going through immediately.
+		desired := clusterOperator.DeepCopy()
+		desired.Status.Versions = []configv1.OperandVersion{{Name: "operator", Version: "final"}}
+		if err := writer.Write(context.TODO(), desired, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if scheduler.pending == nil {
+			t.Errorf("expected the write to be held behind a cooldown once the burst budget was spent")
+		}
+	})
+
+	t.Run("a failed scheduled write does not panic and leaves the limiter's count intact", func(t *testing.T) {
+		clusterOperator := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "OPERATOR_NAME"}}
+		client := fake.NewSimpleClientset(clusterOperator)
+		client.PrependReactor("update", "clusteroperators", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("injected failure")
+		})
+		limiter := newPhaseRateLimiter(0, 0, 0, time.Hour)
+		writer := newCoalescingStatusWriter(client.ConfigV1(), limiter)
+		scheduler := &fakeScheduler{}
+		writer.afterFunc = scheduler.afterFunc
+
+		if err := writer.Write(context.TODO(), clusterOperator.DeepCopy(), false); err != nil {
+			t.Fatalf("unexpected error scheduling the write: %v", err)
+		}
+		// The write itself happens inside the scheduled callback, so its error can't surface
+		// through Write's return value -- it can only be observed via the limiter/logs.
+		scheduler.advance()
+
+		if got := limiter.NumRequeues(clusterOperator.Name); got == 0 {
+			t.Errorf("expected a failed write to leave the limiter's count intact, got 0")
+		}
+	})
+
+	t.Run("force bypasses the cooldown", func(t *testing.T) {
+		clusterOperator := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "OPERATOR_NAME"}}
+		client := fake.NewSimpleClientset(clusterOperator)
+		writer := newCoalescingStatusWriter(client.ConfigV1(), constantRateLimiter{delay: time.Hour})
+
+		if err := writer.Write(context.TODO(), clusterOperator.DeepCopy(), false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := writer.Write(context.TODO(), clusterOperator.DeepCopy(), true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if actions := len(client.Actions()); actions != 1 {
+			t.Errorf("expected the forced write to happen immediately, got %d API calls", actions)
+		}
+	})
+
+	t.Run("flush forces a pending write through", func(t *testing.T) {
+		clusterOperator := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "OPERATOR_NAME"}}
+		client := fake.NewSimpleClientset(clusterOperator)
+		writer := newCoalescingStatusWriter(client.ConfigV1(), constantRateLimiter{delay: time.Hour})
+
+		if err := writer.Write(context.TODO(), clusterOperator.DeepCopy(), false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if actions := len(client.Actions()); actions != 0 {
+			t.Fatalf("expected the write to be held back, got %d API calls", actions)
+		}
+
+		if err := writer.Flush(context.TODO()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if actions := len(client.Actions()); actions != 1 {
+			t.Errorf("expected Flush to issue the pending write, got %d API calls", actions)
+		}
+	})
+}
@@ -0,0 +1,320 @@
+// Package status reconciles an operator's OperatorStatus onto a ClusterOperator resource, so
+// that cluster-wide consumers (the cluster-version operator, console, alerting) have a single
+// place to read an operator's Degraded/Progressing/Available conditions and operand versions
+// from.
+package status
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"k8s.io/client-go/util/workqueue"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+
+	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	operatorv1helpers "github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// StatusSyncer maintains a ClusterOperator resource in lock-step with an operator's
+// OperatorStatus: it aggregates the operator's "*Degraded"/"*Progressing"/"*Available"
+// conditions into the corresponding ClusterOperator conditions, and copies over related
+// objects and operand versions.
+type StatusSyncer struct {
+	clusterOperatorName string
+
+	relatedObjects     []configv1.ObjectReference
+	relatedObjectsFunc func() (isSet bool, objects []configv1.ObjectReference)
+
+	versionGetter         VersionGetter
+	operatorClient        operatorv1helpers.OperatorClient
+	clusterOperatorClient configv1client.ClusterOperatorsGetter
+	clusterOperatorLister configv1listers.ClusterOperatorLister
+
+	degradedInertia    InertiaFunc
+	progressingInertia InertiaFunc
+	availableInertia   InertiaFunc
+
+	mergeStrategies map[configv1.ClusterStatusConditionType]MergeStrategy
+
+	conditionOverrides []conditionOverrideEntry
+
+	workloadStatusSource *workloadStatusSource
+
+	updateWriter *coalescingStatusWriter
+}
+
+// NewClusterOperatorStatusController returns a StatusSyncer for the named ClusterOperator.
+func NewClusterOperatorStatusController(
+	name string,
+	relatedObjects []configv1.ObjectReference,
+	clusterOperatorClient configv1client.ClusterOperatorsGetter,
+	clusterOperatorLister configv1listers.ClusterOperatorLister,
+	operatorClient operatorv1helpers.OperatorClient,
+	versionGetter VersionGetter,
+) *StatusSyncer {
+	return &StatusSyncer{
+		clusterOperatorName:   name,
+		relatedObjects:        relatedObjects,
+		clusterOperatorClient: clusterOperatorClient,
+		clusterOperatorLister: clusterOperatorLister,
+		operatorClient:        operatorClient,
+		versionGetter:         versionGetter,
+	}
+}
+
+// WithDegradedInertia debounces "*Degraded" operator conditions: a condition only counts
+// toward the aggregated Degraded condition once it has satisfied inertia.
+func (c *StatusSyncer) WithDegradedInertia(inertia InertiaFunc) *StatusSyncer {
+	c.degradedInertia = inertia
+	return c
+}
+
+// WithProgressingInertia debounces "*Progressing" operator conditions the same way
+// WithDegradedInertia debounces "*Degraded" ones.
+func (c *StatusSyncer) WithProgressingInertia(inertia InertiaFunc) *StatusSyncer {
+	c.progressingInertia = inertia
+	return c
+}
+
+// WithAvailableInertia debounces "*Available" operator conditions the same way
+// WithDegradedInertia debounces "*Degraded" ones.
+func (c *StatusSyncer) WithAvailableInertia(inertia InertiaFunc) *StatusSyncer {
+	c.availableInertia = inertia
+	return c
+}
+
+// WithRelatedObjectsFunc registers a dynamic source of related objects. isSet tells
+// StatusSyncer whether objects is meaningful for this sync; when isSet is false, the
+// ClusterOperator's existing related objects (minus the static ones) are left untouched
+// instead of being wiped.
+func (c *StatusSyncer) WithRelatedObjectsFunc(relatedObjectsFunc func() (isSet bool, objects []configv1.ObjectReference)) *StatusSyncer {
+	c.relatedObjectsFunc = relatedObjectsFunc
+	return c
+}
+
+// WithConditionMergeStrategy overrides how the operator conditions feeding conditionType (e.g.
+// configv1.OperatorDegraded) are collapsed into that ClusterOperator condition. Absent an
+// override, a MergeStrategy matching the historical behavior is used.
+func (c *StatusSyncer) WithConditionMergeStrategy(conditionType configv1.ClusterStatusConditionType, strategy MergeStrategy) *StatusSyncer {
+	if c.mergeStrategies == nil {
+		c.mergeStrategies = map[configv1.ClusterStatusConditionType]MergeStrategy{}
+	}
+	c.mergeStrategies[conditionType] = strategy
+	return c
+}
+
+// WithUpdateRateLimiter makes StatusSyncer coalesce ClusterOperator status writes behind
+// limiter instead of issuing one API call per sync: see NewStatusUpdateRateLimiter for the
+// default fast/slow behavior. Status transitions that flip Available or Degraded always
+// bypass the limiter's cooldown.
+func (c *StatusSyncer) WithUpdateRateLimiter(limiter workqueue.RateLimiter) *StatusSyncer {
+	c.updateWriter = newCoalescingStatusWriter(c.clusterOperatorClient, limiter)
+	return c
+}
+
+// Flush forces any ClusterOperator status write that WithUpdateRateLimiter is holding back
+// through immediately. Callers should invoke it on shutdown so a pending status isn't lost
+// behind a cooldown that never gets to fire.
+func (c *StatusSyncer) Flush(ctx context.Context) error {
+	if c.updateWriter == nil {
+		return nil
+	}
+	return c.updateWriter.Flush(ctx)
+}
+
+// Sync implements factory.Controller.
+func (c *StatusSyncer) Sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, currentDetailedStatus, _, err := c.operatorClient.GetOperatorState()
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	originalClusterOperatorObj, err := c.clusterOperatorLister.Get(c.clusterOperatorName)
+	if apierrors.IsNotFound(err) {
+		originalClusterOperatorObj, err = c.clusterOperatorClient.ClusterOperators().Create(ctx, &configv1.ClusterOperator{
+			ObjectMeta: metav1.ObjectMeta{Name: c.clusterOperatorName},
+		}, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	clusterOperatorObj := originalClusterOperatorObj.DeepCopy()
+
+	if c.workloadStatusSource != nil {
+		merged := *currentDetailedStatus
+		merged.Conditions = append(append([]operatorv1.OperatorCondition{}, currentDetailedStatus.Conditions...), c.workloadStatusSource.conditions()...)
+		currentDetailedStatus = &merged
+	}
+
+	if len(c.conditionOverrides) > 0 {
+		overridden := *currentDetailedStatus
+		overridden.Conditions = c.applyConditionOverrides(currentDetailedStatus.Conditions)
+		currentDetailedStatus = &overridden
+	}
+
+	c.syncStatus(clusterOperatorObj, currentDetailedStatus)
+	c.syncRelatedObjects(clusterOperatorObj)
+	c.syncVersions(clusterOperatorObj)
+
+	if apiequality.Semantic.DeepEqual(clusterOperatorObj, originalClusterOperatorObj) {
+		return nil
+	}
+
+	klog.V(2).Infof("Updating status of %s", c.clusterOperatorName)
+	if c.updateWriter == nil {
+		_, updateErr := c.clusterOperatorClient.ClusterOperators().UpdateStatus(ctx, clusterOperatorObj, metav1.UpdateOptions{})
+		return updateErr
+	}
+	force := availableOrDegradedFlipped(originalClusterOperatorObj, clusterOperatorObj)
+	return c.updateWriter.Write(ctx, clusterOperatorObj, force)
+}
+
+// availableOrDegradedFlipped reports whether Available or Degraded's Status differs between
+// the two ClusterOperators; such transitions always bypass the update rate limiter's cooldown.
+func availableOrDegradedFlipped(before, after *configv1.ClusterOperator) bool {
+	for _, conditionType := range []configv1.ClusterStatusConditionType{configv1.OperatorAvailable, configv1.OperatorDegraded} {
+		beforeCondition := v1helpers.FindStatusCondition(before.Status.Conditions, conditionType)
+		afterCondition := v1helpers.FindStatusCondition(after.Status.Conditions, conditionType)
+		beforeStatus := configv1.ConditionUnknown
+		if beforeCondition != nil {
+			beforeStatus = beforeCondition.Status
+		}
+		afterStatus := configv1.ConditionUnknown
+		if afterCondition != nil {
+			afterStatus = afterCondition.Status
+		}
+		if beforeStatus != afterStatus {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *StatusSyncer) syncStatus(clusterOperatorObj *configv1.ClusterOperator, operatorStatus *operatorv1.OperatorStatus) {
+	conditions := []configv1.ClusterOperatorStatusCondition{
+		c.aggregateCondition(configv1.OperatorDegraded, configv1.ConditionFalse, orNoInertia(c.degradedInertia), operatorStatus.Conditions),
+		c.aggregateCondition(configv1.OperatorProgressing, configv1.ConditionFalse, orNoInertia(c.progressingInertia), operatorStatus.Conditions),
+		c.aggregateCondition(configv1.OperatorAvailable, configv1.ConditionTrue, orNoInertia(c.availableInertia), operatorStatus.Conditions),
+		c.aggregateCondition(configv1.OperatorUpgradeable, configv1.ConditionTrue, noInertia, operatorStatus.Conditions),
+	}
+	for _, condition := range conditions {
+		v1helpers.SetStatusCondition(&clusterOperatorObj.Status.Conditions, condition)
+	}
+}
+
+// orNoInertia returns inertia, or noInertia if inertia hasn't been set.
+func orNoInertia(inertia InertiaFunc) InertiaFunc {
+	if inertia != nil {
+		return inertia
+	}
+	return noInertia
+}
+
+// aggregateCondition collapses every operator condition whose Type ends in conditionType's
+// suffix (e.g. "Degraded") into a single ClusterOperator condition.
+func (c *StatusSyncer) aggregateCondition(conditionType configv1.ClusterStatusConditionType, defaultStatus configv1.ConditionStatus, inertia InertiaFunc, operatorConditions []operatorv1.OperatorCondition) configv1.ClusterOperatorStatusCondition {
+	suffix := string(conditionType)
+	var matching []operatorv1.OperatorCondition
+	for _, condition := range operatorConditions {
+		if strings.HasSuffix(condition.Type, suffix) {
+			matching = append(matching, condition)
+		}
+	}
+	if len(matching) == 0 {
+		return configv1.ClusterOperatorStatusCondition{
+			Type:               conditionType,
+			Status:             configv1.ConditionUnknown,
+			Reason:             "NoData",
+			LastTransitionTime: metav1.Now(),
+		}
+	}
+
+	// A condition only counts toward the aggregated status once inertia has elapsed for it,
+	// but once *any* non-default condition does, every currently non-default condition is
+	// reported together -- there's no point hiding a condition that's genuinely bad just
+	// because it hasn't individually outlasted its own grace period.
+	anyCounts := false
+	for _, condition := range matching {
+		if condition.Status != defaultStatus && time.Since(condition.LastTransitionTime.Time) >= inertia(condition) {
+			anyCounts = true
+			break
+		}
+	}
+
+	merged := make([]MergedCondition, 0, len(matching))
+	for _, condition := range matching {
+		merged = append(merged, MergedCondition{
+			OperatorCondition: condition,
+			Counts:            anyCounts && condition.Status != defaultStatus,
+		})
+	}
+
+	strategy := c.mergeStrategies[conditionType]
+	if strategy == nil {
+		strategy = defaultMergeStrategy{}
+	}
+	status, reason, message := strategy.Merge(conditionType, defaultStatus, merged)
+
+	return configv1.ClusterOperatorStatusCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+}
+
+func (c *StatusSyncer) syncRelatedObjects(clusterOperatorObj *configv1.ClusterOperator) {
+	var relatedObjects []configv1.ObjectReference
+	if c.relatedObjectsFunc != nil {
+		if isSet, dynamicRelatedObjects := c.relatedObjectsFunc(); isSet {
+			relatedObjects = append(relatedObjects, dynamicRelatedObjects...)
+		} else {
+			// the dynamic source has nothing to say this round; don't wipe whatever it
+			// reported last time.
+			relatedObjects = append(relatedObjects, clusterOperatorObj.Status.RelatedObjects...)
+		}
+	}
+	for _, static := range c.relatedObjects {
+		found := false
+		for _, existing := range relatedObjects {
+			if existing == static {
+				found = true
+				break
+			}
+		}
+		if !found {
+			relatedObjects = append(relatedObjects, static)
+		}
+	}
+	clusterOperatorObj.Status.RelatedObjects = relatedObjects
+}
+
+func (c *StatusSyncer) syncVersions(clusterOperatorObj *configv1.ClusterOperator) {
+	versions := c.versionGetter.GetVersions()
+	if len(versions) == 0 {
+		return
+	}
+	operandVersions := make([]configv1.OperandVersion, 0, len(versions))
+	for name, version := range versions {
+		operandVersions = append(operandVersions, configv1.OperandVersion{Name: name, Version: version})
+	}
+	sort.Slice(operandVersions, func(i, j int) bool { return operandVersions[i].Name < operandVersions[j].Name })
+	clusterOperatorObj.Status.Versions = operandVersions
+}
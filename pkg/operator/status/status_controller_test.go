@@ -31,12 +31,16 @@ func TestDegraded(t *testing.T) {
 	yesterday := metav1.NewTime(time.Now().Add(-24 * time.Hour))
 
 	testCases := []struct {
-		name             string
-		conditions       []operatorv1.OperatorCondition
-		expectedType     configv1.ClusterStatusConditionType
-		expectedStatus   configv1.ConditionStatus
-		expectedMessages []string
-		expectedReason   string
+		name                   string
+		conditions             []operatorv1.OperatorCondition
+		progressingInertia     InertiaFunc
+		availableInertia       InertiaFunc
+		conditionOverrideRegex *regexp.Regexp
+		conditionOverride      ConditionOverride
+		expectedType           configv1.ClusterStatusConditionType
+		expectedStatus         configv1.ConditionStatus
+		expectedMessages       []string
+		expectedReason         string
 	}{
 		{
 			name:           "no data",
@@ -270,6 +274,100 @@ func TestDegraded(t *testing.T) {
 				"TypeBAvailable: b is confused",
 			},
 		},
+		{
+			name: "progressing/beyond inertia threshold",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeAProgressing", Status: operatorv1.ConditionTrue, LastTransitionTime: threeMinutesAgo, Message: "a message from type a"},
+			},
+			progressingInertia: MustNewInertia(2 * time.Minute).Inertia,
+			expectedType:       configv1.OperatorProgressing,
+			expectedStatus:     configv1.ConditionTrue,
+			expectedReason:     "TypeA",
+			expectedMessages: []string{
+				"TypeAProgressing: a message from type a",
+			},
+		},
+		{
+			name: "progressing/within inertia threshold",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeAProgressing", Status: operatorv1.ConditionTrue, LastTransitionTime: fiveSecondsAgo, Message: "a message from type a"},
+			},
+			progressingInertia: MustNewInertia(2 * time.Minute).Inertia,
+			expectedType:       configv1.OperatorProgressing,
+			expectedStatus:     configv1.ConditionFalse,
+			expectedReason:     "AsExpected",
+			expectedMessages: []string{
+				"TypeAProgressing: a message from type a",
+			},
+		},
+		{
+			name: "available/beyond inertia threshold",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeAAvailable", Status: operatorv1.ConditionFalse, LastTransitionTime: threeMinutesAgo, Message: "a message from type a"},
+			},
+			availableInertia: MustNewInertia(30 * time.Second).Inertia,
+			expectedType:     configv1.OperatorAvailable,
+			expectedStatus:   configv1.ConditionFalse,
+			expectedReason:   "TypeA",
+			expectedMessages: []string{
+				"TypeAAvailable: a message from type a",
+			},
+		},
+		{
+			name: "available/within inertia threshold",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeAAvailable", Status: operatorv1.ConditionFalse, LastTransitionTime: fiveSecondsAgo, Message: "a message from type a"},
+			},
+			availableInertia: MustNewInertia(30 * time.Second).Inertia,
+			expectedType:     configv1.OperatorAvailable,
+			expectedStatus:   configv1.ConditionTrue,
+			expectedReason:   "AsExpected",
+			expectedMessages: []string{
+				"TypeAAvailable: a message from type a",
+			},
+		},
+		{
+			name: "available/inverted polarity/beyond threshold",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeEAvailable", Status: operatorv1.ConditionTrue, LastTransitionTime: threeMinutesAgo, Message: "a message from type e"},
+			},
+			conditionOverrideRegex: regexp.MustCompile("^TypeEAvailable$"),
+			conditionOverride:      ConditionOverride{Polarity: true},
+			expectedType:           configv1.OperatorAvailable,
+			expectedStatus:         configv1.ConditionFalse,
+			expectedReason:         "TypeE",
+			expectedMessages: []string{
+				"TypeEAvailable: a message from type e",
+			},
+		},
+		{
+			name: "degraded/warning severity downgrades to progressing",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "TypeFDegraded", Status: operatorv1.ConditionTrue, LastTransitionTime: threeMinutesAgo, Message: "a message from type f"},
+			},
+			conditionOverrideRegex: regexp.MustCompile("^(TypeF)Degraded$"),
+			conditionOverride:      ConditionOverride{Severity: SeverityWarning},
+			expectedType:           configv1.OperatorProgressing,
+			expectedStatus:         configv1.ConditionTrue,
+			expectedReason:         "TypeF",
+			expectedMessages: []string{
+				"TypeFProgressing: [warning] a message from type f",
+			},
+		},
+		{
+			name: "warning condition redirected to a different aggregated type",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "FooWarning", Status: operatorv1.ConditionFalse, LastTransitionTime: threeMinutesAgo, Message: "a message from foo"},
+			},
+			conditionOverrideRegex: regexp.MustCompile("^(Foo)Warning$"),
+			conditionOverride:      ConditionOverride{Severity: SeverityWarning, OverrideOutputType: configv1.OperatorUpgradeable},
+			expectedType:           configv1.OperatorUpgradeable,
+			expectedStatus:         configv1.ConditionFalse,
+			expectedReason:         "Foo",
+			expectedMessages: []string{
+				"FooUpgradeable: [warning] a message from foo",
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -313,6 +411,15 @@ func TestDegraded(t *testing.T) {
 					Duration:             time.Minute,
 				},
 			).Inertia)
+			if tc.progressingInertia != nil {
+				controller = controller.WithProgressingInertia(tc.progressingInertia)
+			}
+			if tc.availableInertia != nil {
+				controller = controller.WithAvailableInertia(tc.availableInertia)
+			}
+			if tc.conditionOverrideRegex != nil {
+				controller = controller.WithConditionOverride(tc.conditionOverrideRegex, tc.conditionOverride)
+			}
 			if err := controller.Sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("status"))); err != nil {
 				t.Errorf("unexpected sync error: %v", err)
 				return
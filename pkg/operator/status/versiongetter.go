@@ -0,0 +1,58 @@
+package status
+
+import "sync"
+
+// VersionGetter allows an operator to report the versions of the operands it manages so that
+// StatusSyncer can stamp them onto the ClusterOperator's status.versions.
+type VersionGetter interface {
+	// SetVersion records the currently achieved version for the given operand.
+	SetVersion(operandName, version string)
+	// GetVersions returns the currently known versions, keyed by operand name.
+	GetVersions() map[string]string
+	// VersionChangedChannel returns a channel that is closed the next time SetVersion
+	// actually changes a version, so callers can wake a sync loop instead of polling.
+	VersionChangedChannel() <-chan struct{}
+}
+
+type versionGetter struct {
+	lock     sync.Mutex
+	versions map[string]string
+	notify   chan struct{}
+}
+
+// NewVersionGetter returns a VersionGetter backed by an in-memory map.
+func NewVersionGetter() VersionGetter {
+	return &versionGetter{
+		versions: map[string]string{},
+		notify:   make(chan struct{}),
+	}
+}
+
+func (v *versionGetter) SetVersion(operandName, version string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.versions[operandName] == version {
+		return
+	}
+	v.versions[operandName] = version
+	close(v.notify)
+	v.notify = make(chan struct{})
+}
+
+func (v *versionGetter) GetVersions() map[string]string {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	ret := make(map[string]string, len(v.versions))
+	for k, val := range v.versions {
+		ret[k] = val
+	}
+	return ret
+}
+
+func (v *versionGetter) VersionChangedChannel() <-chan struct{} {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return v.notify
+}
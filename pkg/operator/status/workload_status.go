@@ -0,0 +1,229 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+// Operator condition types synthesized by a workload status source. They feed
+// StatusSyncer's aggregation the same way any other operator condition would.
+const (
+	WorkloadsAvailableCondition   = "WorkloadsAvailable"
+	WorkloadsProgressingCondition = "WorkloadsProgressing"
+	WorkloadsDegradedCondition    = "WorkloadsDegraded"
+)
+
+// defaultNotYetScheduledGrace is how long a workload with zero ready replicas is given before
+// it starts contributing to WorkloadsDegraded, mirroring how long a rollout can reasonably sit
+// unscheduled before it's worth alerting on.
+const defaultNotYetScheduledGrace = 10 * time.Minute
+
+// workloadStatusSource watches Deployments, DaemonSets and StatefulSets in a namespace and
+// synthesizes operator conditions describing their rollout status, so operator authors don't
+// each have to reimplement pod-liveness tracking. This mirrors the SetFromPods pattern used by
+// cluster-network-operator's StatusManager.
+type workloadStatusSource struct {
+	namespace string
+	selector  labels.Selector
+	informers []cache.SharedIndexInformer
+
+	notYetScheduledGrace time.Duration
+
+	now func() time.Time
+
+	lock      sync.Mutex
+	lastKnown map[string]operatorv1.OperatorCondition
+}
+
+// WithWorkloadStatusSource registers a source that watches Deployments/DaemonSets/StatefulSets
+// matching selector in namespace and synthesizes WorkloadsAvailable/WorkloadsProgressing/
+// WorkloadsDegraded operator conditions from their rollout status. Those conditions are merged
+// into the operator's conditions before every sync, so they flow through the normal
+// aggregation (and inertia/merge-strategy) machinery like any operator-reported condition.
+func (c *StatusSyncer) WithWorkloadStatusSource(namespace string, selector labels.Selector, informers ...cache.SharedIndexInformer) *StatusSyncer {
+	c.workloadStatusSource = &workloadStatusSource{
+		namespace:            namespace,
+		selector:             selector,
+		informers:            informers,
+		notYetScheduledGrace: defaultNotYetScheduledGrace,
+		now:                  time.Now,
+	}
+	return c
+}
+
+// conditions synthesizes the current WorkloadsAvailable/WorkloadsProgressing/WorkloadsDegraded
+// operator conditions from whatever Deployments/DaemonSets/StatefulSets the registered
+// informers currently know about.
+func (w *workloadStatusSource) conditions() []operatorv1.OperatorCondition {
+	var rollouts []workloadRollout
+	for _, informer := range w.informers {
+		for _, obj := range informer.GetStore().List() {
+			if rollout, ok := w.rolloutFor(obj); ok {
+				rollouts = append(rollouts, rollout)
+			}
+		}
+	}
+	sort.Slice(rollouts, func(i, j int) bool { return rollouts[i].name < rollouts[j].name })
+
+	nowFn := w.now
+	if nowFn == nil {
+		nowFn = time.Now
+	}
+
+	var notReady, notUpdated, notScheduled []string
+	for _, rollout := range rollouts {
+		message := fmt.Sprintf("%s %s has %d/%d replicas ready", rollout.kind, rollout.name, rollout.ready, rollout.desired)
+		if rollout.ready < rollout.desired {
+			notReady = append(notReady, message)
+		}
+		if rollout.updated < rollout.desired {
+			notUpdated = append(notUpdated, message)
+		}
+		if rollout.desired > 0 && rollout.ready == 0 && nowFn().Sub(rollout.created) > w.notYetScheduledGrace {
+			notScheduled = append(notScheduled, fmt.Sprintf("%s %s has not scheduled any replicas since %s", rollout.kind, rollout.name, rollout.created.Format(time.RFC3339)))
+		}
+	}
+
+	available := operatorv1.OperatorCondition{
+		Type:   WorkloadsAvailableCondition,
+		Status: operatorv1.ConditionTrue,
+		Reason: "AsExpected",
+	}
+	if len(notReady) > 0 {
+		available.Status = operatorv1.ConditionFalse
+		available.Reason = "WorkloadNotReady"
+		available.Message = strings.Join(notReady, "\n")
+	}
+
+	progressing := operatorv1.OperatorCondition{
+		Type:   WorkloadsProgressingCondition,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(notUpdated) > 0 {
+		progressing.Status = operatorv1.ConditionTrue
+		progressing.Reason = "RolloutInProgress"
+		progressing.Message = strings.Join(notUpdated, "\n")
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   WorkloadsDegradedCondition,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(notScheduled) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "WorkloadNotScheduled"
+		degraded.Message = strings.Join(notScheduled, "\n")
+	}
+
+	now := metav1.NewTime(nowFn())
+	return []operatorv1.OperatorCondition{
+		w.stamp(available, now),
+		w.stamp(progressing, now),
+		w.stamp(degraded, now),
+	}
+}
+
+// stamp fills in condition's LastTransitionTime from the last call's result for the same
+// condition type, only advancing it to now when the derived status actually changed. This keeps
+// workload-derived conditions honoring inertia (WithDegradedInertia and friends) the same way a
+// directly operator-reported condition would.
+func (w *workloadStatusSource) stamp(condition operatorv1.OperatorCondition, now metav1.Time) operatorv1.OperatorCondition {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.lastKnown == nil {
+		w.lastKnown = map[string]operatorv1.OperatorCondition{}
+	}
+	if previous, ok := w.lastKnown[condition.Type]; ok && previous.Status == condition.Status {
+		condition.LastTransitionTime = previous.LastTransitionTime
+	} else {
+		condition.LastTransitionTime = now
+	}
+	w.lastKnown[condition.Type] = condition
+	return condition
+}
+
+type workloadRollout struct {
+	kind    string
+	name    string
+	created time.Time
+	desired int32
+	ready   int32
+	updated int32
+}
+
+func (w *workloadStatusSource) rolloutFor(obj interface{}) (workloadRollout, bool) {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		if !w.matches(workload.Namespace, workload.Labels) {
+			return workloadRollout{}, false
+		}
+		desired := int32(1)
+		if workload.Spec.Replicas != nil {
+			desired = *workload.Spec.Replicas
+		}
+		return workloadRollout{
+			kind:    "Deployment",
+			name:    workload.Namespace + "/" + workload.Name,
+			created: workload.CreationTimestamp.Time,
+			desired: desired,
+			ready:   workload.Status.ReadyReplicas,
+			updated: workload.Status.UpdatedReplicas,
+		}, true
+
+	case *appsv1.DaemonSet:
+		if !w.matches(workload.Namespace, workload.Labels) {
+			return workloadRollout{}, false
+		}
+		return workloadRollout{
+			kind:    "DaemonSet",
+			name:    workload.Namespace + "/" + workload.Name,
+			created: workload.CreationTimestamp.Time,
+			desired: workload.Status.DesiredNumberScheduled,
+			ready:   workload.Status.NumberReady,
+			updated: workload.Status.UpdatedNumberScheduled,
+		}, true
+
+	case *appsv1.StatefulSet:
+		if !w.matches(workload.Namespace, workload.Labels) {
+			return workloadRollout{}, false
+		}
+		desired := int32(1)
+		if workload.Spec.Replicas != nil {
+			desired = *workload.Spec.Replicas
+		}
+		return workloadRollout{
+			kind:    "StatefulSet",
+			name:    workload.Namespace + "/" + workload.Name,
+			created: workload.CreationTimestamp.Time,
+			desired: desired,
+			ready:   workload.Status.ReadyReplicas,
+			updated: workload.Status.UpdatedReplicas,
+		}, true
+
+	default:
+		return workloadRollout{}, false
+	}
+}
+
+func (w *workloadStatusSource) matches(namespace string, objLabels map[string]string) bool {
+	if w.namespace != "" && w.namespace != namespace {
+		return false
+	}
+	if w.selector == nil {
+		return true
+	}
+	return w.selector.Matches(labels.Set(objLabels))
+}
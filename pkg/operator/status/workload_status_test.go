@@ -0,0 +1,200 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+// fakeInformer is a minimal cache.SharedIndexInformer stand-in good enough to back a
+// Store: workloadStatusSource only ever calls GetStore().
+type fakeInformer struct {
+	cache.SharedIndexInformer
+	store cache.Store
+}
+
+func newFakeInformer(objs ...interface{}) *fakeInformer {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for _, obj := range objs {
+		_ = store.Add(obj)
+	}
+	return &fakeInformer{store: store}
+}
+
+func (f *fakeInformer) GetStore() cache.Store {
+	return f.store
+}
+
+func replicas(n int32) *int32 { return &n }
+
+func TestWorkloadStatusSourceConditions(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	longAgo := now.Add(-time.Hour)
+	justNow := now.Add(-time.Second)
+
+	readyDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "openshift-foo", CreationTimestamp: metav1.NewTime(longAgo), Labels: map[string]string{"app": "foo"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: replicas(3)},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 3, UpdatedReplicas: 3},
+	}
+	rollingDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "rolling", Namespace: "openshift-foo", CreationTimestamp: metav1.NewTime(longAgo), Labels: map[string]string{"app": "foo"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: replicas(3)},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 2},
+	}
+	unscheduledDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "openshift-foo", CreationTimestamp: metav1.NewTime(longAgo), Labels: map[string]string{"app": "foo"}},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 0},
+	}
+	justScheduledDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "baz", Namespace: "openshift-foo", CreationTimestamp: metav1.NewTime(justNow), Labels: map[string]string{"app": "foo"}},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 0},
+	}
+	otherNamespace := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "elsewhere", Namespace: "other", CreationTimestamp: metav1.NewTime(longAgo), Labels: map[string]string{"app": "foo"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: replicas(1)},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 0, UpdatedReplicas: 0},
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{"app": "foo"})
+
+	testCases := []struct {
+		name                string
+		objects             []interface{}
+		expectedAvailable   bool
+		expectedProgressing bool
+		expectedDegraded    bool
+	}{
+		{
+			name:                "everything ready",
+			objects:             []interface{}{readyDeployment},
+			expectedAvailable:   true,
+			expectedProgressing: false,
+			expectedDegraded:    false,
+		},
+		{
+			name:                "rollout in progress",
+			objects:             []interface{}{rollingDeployment},
+			expectedAvailable:   false,
+			expectedProgressing: true,
+			expectedDegraded:    false,
+		},
+		{
+			name:                "not yet scheduled beyond grace period",
+			objects:             []interface{}{unscheduledDaemonSet},
+			expectedAvailable:   false,
+			expectedProgressing: true,
+			expectedDegraded:    true,
+		},
+		{
+			name:                "not yet scheduled within grace period",
+			objects:             []interface{}{justScheduledDaemonSet},
+			expectedAvailable:   false,
+			expectedProgressing: true,
+			expectedDegraded:    false,
+		},
+		{
+			name:                "objects outside the namespace/selector are ignored",
+			objects:             []interface{}{readyDeployment, otherNamespace},
+			expectedAvailable:   true,
+			expectedProgressing: false,
+			expectedDegraded:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := &workloadStatusSource{
+				namespace:            "openshift-foo",
+				selector:             selector,
+				informers:            []cache.SharedIndexInformer{newFakeInformer(tc.objects...)},
+				notYetScheduledGrace: 10 * time.Minute,
+				now:                  func() time.Time { return now },
+			}
+
+			conditions := source.conditions()
+			if len(conditions) != 3 {
+				t.Fatalf("expected 3 conditions, got %d", len(conditions))
+			}
+
+			for _, condition := range conditions {
+				switch condition.Type {
+				case WorkloadsAvailableCondition:
+					if (condition.Status == "True") != tc.expectedAvailable {
+						t.Errorf("unexpected %s: %+v", condition.Type, condition)
+					}
+				case WorkloadsProgressingCondition:
+					if (condition.Status == "True") != tc.expectedProgressing {
+						t.Errorf("unexpected %s: %+v", condition.Type, condition)
+					}
+				case WorkloadsDegradedCondition:
+					if (condition.Status == "True") != tc.expectedDegraded {
+						t.Errorf("unexpected %s: %+v", condition.Type, condition)
+					}
+				default:
+					t.Errorf("unexpected condition type %s", condition.Type)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkloadStatusSourceConditionsPreserveLastTransitionTime(t *testing.T) {
+	selector := labels.SelectorFromSet(labels.Set{"app": "foo"})
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "openshift-foo", Labels: map[string]string{"app": "foo"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: replicas(3)},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 3, UpdatedReplicas: 3},
+	}
+	informer := newFakeInformer(deployment)
+
+	t0 := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := t0
+	source := &workloadStatusSource{
+		namespace:            "openshift-foo",
+		selector:             selector,
+		informers:            []cache.SharedIndexInformer{informer},
+		notYetScheduledGrace: 10 * time.Minute,
+		now:                  func() time.Time { return clock },
+	}
+
+	first := source.conditions()
+	for _, condition := range first {
+		if !condition.LastTransitionTime.Time.Equal(t0) {
+			t.Fatalf("expected %s to transition at %s on first sync, got %s", condition.Type, t0, condition.LastTransitionTime)
+		}
+	}
+
+	// Unchanged status on a later sync must not bump LastTransitionTime, or inertia checks
+	// like aggregateCondition's time.Since(condition.LastTransitionTime) would never elapse.
+	clock = clock.Add(time.Hour)
+	second := source.conditions()
+	for _, condition := range second {
+		if !condition.LastTransitionTime.Time.Equal(t0) {
+			t.Errorf("expected %s LastTransitionTime to stay at %s when status didn't change, got %s", condition.Type, t0, condition.LastTransitionTime)
+		}
+	}
+
+	// A genuine status flip must advance LastTransitionTime to the sync that observed it.
+	deployment.Status.ReadyReplicas = 1
+	_ = informer.store.Update(deployment)
+	clock = clock.Add(time.Hour)
+	third := source.conditions()
+	for _, condition := range third {
+		if condition.Type != WorkloadsAvailableCondition {
+			continue
+		}
+		if condition.Status != operatorv1.ConditionFalse {
+			t.Fatalf("expected %s to flip to False, got %s", condition.Type, condition.Status)
+		}
+		if !condition.LastTransitionTime.Time.Equal(clock) {
+			t.Errorf("expected %s to transition at %s after flipping, got %s", condition.Type, clock, condition.LastTransitionTime)
+		}
+	}
+}